@@ -1,8 +1,15 @@
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 
 	comp "github.com/rudrcodes/File_Compressor_Go/pkg/compressor"
 )
@@ -10,154 +17,370 @@ import (
 //Commands to run various functions :
 
 /*
-* # Compress a single file
-* go run main.go compress document.txt document.txt.gz
+* # Compress a single file, auto-picks a command and output path
+* go run main.go document.txt
 
 * # Compress with maximum compression
-* go run main.go compress document.txt document.txt.gz 9
+* go run main.go -l 9 document.txt document.txt.gz
 
-* # Decompress a file
-* go run main.go decompress document.txt.gz restored.txt
+* # Decompress a file (auto-detected from the .gz suffix)
+* go run main.go document.txt.gz
 
 * # Compress entire directory
 * go run main.go compress-dir ./documents ./compressed_docs
 
+* # Stream through stdin/stdout like gzip
+* cat document.txt | go run main.go compress - - > document.txt.gz
  */
-// CompressionStats holds statistics about the compression operation
+
+// knownCommands are the explicit verbs accepted as the first positional
+// argument; anything else is treated as an input path and the command is
+// auto-detected.
+var knownCommands = map[string]bool{
+	"compress":       true,
+	"decompress":     true,
+	"compress-dir":   true,
+	"decompress-dir": true,
+	"archive":        true,
+	"unarchive":      true,
+}
 
 func main() {
+	// No arguments at all: fall back to the original interactive menu.
+	if len(os.Args) == 1 {
+		runInteractive()
+		return
+	}
 
-	// first get the option from the user what it has to do and then the filenames based on what the user has to do
+	level := flag.Int("l", 6, "compression level 1-9 (1=fastest, 9=best compression)")
+	keep := flag.Bool("k", false, "keep the original input instead of deleting it")
+	force := flag.Bool("f", false, "overwrite the output path if it already exists")
+	output := flag.String("o", "", "output path (defaults to next to the input)")
+	password := flag.String("password", "", "password for AES-256-GCM encryption (prefer -password-stdin; this is visible in shell history and ps)")
+	passwordStdin := flag.Bool("password-stdin", false, "prompt for the password on stdin instead of passing it on the command line")
+	flag.Parse()
 
-	// var option string
+	args := flag.Args()
+	if len(args) == 0 {
+		comp.PrintUsage()
+		os.Exit(1)
+	}
 
-	var command string
-	var inputPath string
-	var outputPath string
-	fmt.Println("What to do today?")
-	fmt.Println("** OPTIONS **")
-	fmt.Println("1) Compress File")
-	fmt.Println("2) Compress Folder")
-	fmt.Println("3) De-Compress File")
-	fmt.Println("4) De-Compress Folder")
+	command := ""
+	if knownCommands[args[0]] {
+		command = args[0]
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		fmt.Println("Error: missing input path")
+		comp.PrintUsage()
+		os.Exit(1)
+	}
 
-	//ask the user to input an option
+	inputPath := args[0]
+	outputPath := *output
+	if outputPath == "" && len(args) > 1 {
+		outputPath = args[1]
+	}
 
-	m := make(map[string]string)
-	m["1"] = "compress"
-	m["2"] = "compress-dir"
-	m["3"] = "decompress"
-	m["4"] = "decompress-dir"
+	// Piping bypasses the file-based FileCompressor API entirely, since
+	// it has no filesystem path to stat for the directory/archive modes.
+	if inputPath == "-" || outputPath == "-" {
+		if command == "" {
+			command = "compress"
+		}
+		if err := runPipe(*level, command, inputPath, outputPath); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
 
-	fmt.Scanln(&command)
+	if command == "" {
+		var err error
+		command, err = detectCommand(inputPath)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			comp.PrintUsage()
+			os.Exit(1)
+		}
+	}
 
-	var inputType string
+	outputPath, err := resolveOutputPath(command, inputPath, outputPath)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		comp.PrintUsage()
+		os.Exit(1)
+	}
 
-	if command == "1" || command == "3" {
-		//take file names
-		inputType = "File"
-	} else {
-		//take folder names
-		inputType = "Folder"
+	if !*force {
+		if _, err := os.Stat(outputPath); err == nil {
+			fmt.Printf("Error: %s already exists (use -f to overwrite)\n", outputPath)
+			os.Exit(1)
+		}
+	}
 
+	compressor := comp.NewFileCompressor(*level)
+	compressor.Password = *password
+	if *passwordStdin {
+		compressor.Password = promptPassword()
 	}
 
-	fmt.Printf("Enter %s names : \n", inputType)
-	fmt.Printf("Input %s  : \n", inputType)
-	fmt.Scanln(&inputPath)
-	fmt.Printf("Output %s  : \n", inputType)
-	fmt.Scanln(&outputPath)
+	if err := runCommand(compressor, command, inputPath, outputPath); err != nil {
+		// The input turned out to be encrypted and no password was ever
+		// supplied: prompt for one instead of just failing, rather than
+		// forcing every unencrypted run through an up-front prompt.
+		if errors.Is(err, comp.ErrPasswordRequired) && compressor.Password == "" {
+			compressor.Password = promptPassword()
+			err = runCommand(compressor, command, inputPath, outputPath)
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
-	// fmt.Println("os.args : ", os.Args)
-	// if len(os.Args) < 4 {
-	// 	PrintUsage()
-	// 	os.Exit(1)
-	// }
+	if !*keep && (command == "compress" || command == "decompress") {
+		if err := os.Remove(inputPath); err != nil {
+			fmt.Printf("Warning: failed to remove original file %s: %v\n", inputPath, err)
+		}
+	}
+}
 
-	// command := os.Args[1]
-	// inputPath := os.Args[2]
-	// outputPath := os.Args[3]
+// promptPassword reads a password from stdin without it ever touching
+// argv, shell history, or /proc/<pid>/cmdline.
+func promptPassword() string {
+	fmt.Print("Password: ")
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimRight(line, "\r\n")
+}
 
-	// Default compression level
+// detectCommand auto-selects compress vs decompress from the input's
+// extension, the way gzip does when handed a bare filename.
+func detectCommand(inputPath string) (string, error) {
+	info, err := os.Stat(inputPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat %s: %v", inputPath, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("%s is a directory; use compress-dir, decompress-dir, archive or unarchive explicitly", inputPath)
+	}
+	if strings.HasSuffix(inputPath, ".gz") {
+		return "decompress", nil
+	}
+	return "compress", nil
+}
 
-	fmt.Println("Command:", command)
-	fmt.Println("inputPath:", inputPath)
-	fmt.Println("outputPath:", outputPath)
-	compressionLevel := 6
+// resolveOutputPath fills in outputPath when it's empty or points at an
+// existing directory, deriving a name from the input's basename: append
+// ".gz" when compressing/archiving, strip it when decompressing.
+// compress-dir/decompress-dir have no such distinct derivation (the output
+// is itself a directory tree, not a single renamed file), so they require
+// an explicit output path instead of silently reusing inputPath.
+func resolveOutputPath(command, inputPath, outputPath string) (string, error) {
+	base := filepath.Base(inputPath)
+
+	derive := func(dir string) string {
+		switch command {
+		case "compress":
+			return filepath.Join(dir, base+".gz")
+		case "decompress":
+			return filepath.Join(dir, strings.TrimSuffix(base, ".gz"))
+		case "archive":
+			return filepath.Join(dir, base+".tar.gz")
+		case "unarchive":
+			return filepath.Join(dir, strings.TrimSuffix(base, ".tar.gz"))
+		default:
+			return filepath.Join(dir, base)
+		}
+	}
 
-	// Parse compression level if provided
-	if len(os.Args) > 4 && (command == "compress" || command == "compress-dir") {
-		if level := os.Args[4]; level != "" {
-			if l := int(level[0] - '0'); l >= 1 && l <= 9 {
-				compressionLevel = l
-			}
+	if command == "compress-dir" || command == "decompress-dir" {
+		if outputPath == "" {
+			return "", fmt.Errorf("%s requires an explicit output directory", command)
 		}
+		// outputPath is itself the directory root to write into, whether
+		// or not it already exists - never nest it under its own basename.
+		return outputPath, nil
 	}
 
-	// creates a FileCompressor struct
-	compressor := comp.NewFileCompressor(compressionLevel)
+	if outputPath == "" {
+		return derive(filepath.Dir(inputPath)), nil
+	}
+	if info, err := os.Stat(outputPath); err == nil && info.IsDir() {
+		return derive(outputPath), nil
+	}
+	return outputPath, nil
+}
 
-	switch m[command] {
+// runCommand dispatches to the FileCompressor method for command,
+// printing the same progress/summary lines the old switch statement did.
+func runCommand(compressor *comp.FileCompressor, command, inputPath, outputPath string) error {
+	switch command {
 	case "compress":
-		fmt.Printf("Compressing %s to %s (level %d)...\n", inputPath, outputPath, compressionLevel)
-
+		fmt.Printf("Compressing %s to %s...\n", inputPath, outputPath)
 		stats, err := compressor.CompressFile(inputPath, outputPath)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-
-			// This function immediately terminates your program and returns an exit code to the operating system.
-			os.Exit(1)
+			return err
 		}
-
 		fmt.Printf("✓ Compression completed successfully!\n")
-
 		comp.PrintStats(stats, "Compression")
 
 	case "decompress":
 		fmt.Printf("Decompressing %s to %s...\n", inputPath, outputPath)
-
 		stats, err := compressor.DecompressFile(inputPath, outputPath)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
-
 		fmt.Printf("✓ Decompression completed successfully!\n")
 		comp.PrintStats(stats, "Decompression")
 
 	case "compress-dir":
-		fmt.Printf("Compressing directory %s to %s (level %d)...\n", inputPath, outputPath, compressionLevel)
+		fmt.Printf("Compressing directory %s to %s...\n", inputPath, outputPath)
+		dirStats, err := compressor.CompressDirectory(inputPath, outputPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Directory compression completed successfully! (%d files, %d failed)\n",
+			len(dirStats.Files), len(dirStats.Failures))
 
-		err := compressor.CompressDirectory(inputPath, outputPath)
+	case "decompress-dir":
+		fmt.Printf("Decompressing directory %s to %s...\n", inputPath, outputPath)
+		dirStats, err := compressor.DecompressDirectory(inputPath, outputPath)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
+		fmt.Printf("✓ Decompression completed successfully! (%d files, %d failed)\n",
+			len(dirStats.Files), len(dirStats.Failures))
 
-		fmt.Printf("✓ Directory compression completed successfully!\n")
+	case "archive":
+		fmt.Printf("Archiving %s to %s...\n", inputPath, outputPath)
+		stats, err := compressor.CompressDirectoryArchive(inputPath, outputPath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ Archive completed successfully!\n")
+		comp.PrintStats(stats, "Compression")
 
-	case "decompress-dir":
-		fmt.Printf("Decompressing Directory %s to %s...\n: ", inputPath, outputPath)
-		err := compressor.DecompressDirectory(inputPath, outputPath)
+	case "unarchive":
+		fmt.Printf("Unarchiving %s to %s...\n", inputPath, outputPath)
+		stats, err := compressor.DecompressArchive(inputPath, outputPath)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			os.Exit(1)
+			return err
 		}
+		fmt.Printf("✓ Unarchive completed successfully!\n")
+		comp.PrintStats(stats, "Decompression")
 
-		fmt.Println("✓ Decompression completed successfully!\n")
-		// stats, err := compressor.DecompressDirectory(inputPath, outputPath)
-		// if err != nil {
-		// 	fmt.Printf("Error: %v\n", err)
-		// 	os.Exit(1)
-		// }
+	default:
+		return fmt.Errorf("unknown command: %s", command)
+	}
+	return nil
+}
 
-		// fmt.Println("✓ Decompression completed successfully!\n")
-		// PrintStats(stats, "Decompression")
+// runPipe streams compress/decompress through stdin/stdout (or a mix of a
+// real path and "-"), bypassing FileCompressor since there's no path to
+// stat for directory/archive modes or for restoring mtimes.
+func runPipe(level int, command, inputPath, outputPath string) error {
+	var in io.Reader = os.Stdin
+	if inputPath != "-" {
+		f, err := os.Open(inputPath)
+		if err != nil {
+			return fmt.Errorf("failed to open input file: %v", err)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	var out io.Writer = os.Stdout
+	if outputPath != "-" && outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	switch command {
+	case "compress":
+		gzWriter, err := gzip.NewWriterLevel(out, level)
+		if err != nil {
+			return fmt.Errorf("failed to create gzip writer: %v", err)
+		}
+		if _, err := io.Copy(gzWriter, in); err != nil {
+			return fmt.Errorf("failed to compress stream: %v", err)
+		}
+		return gzWriter.Close()
+
+	case "decompress":
+		gzReader, err := gzip.NewReader(in)
+		if err != nil {
+			return fmt.Errorf("failed to create gzip reader: %v", err)
+		}
+		defer gzReader.Close()
+		if _, err := io.Copy(out, gzReader); err != nil {
+			return fmt.Errorf("failed to decompress stream: %v", err)
+		}
+		return nil
 
 	default:
+		return fmt.Errorf("command %q doesn't support stdin/stdout piping", command)
+	}
+}
+
+// runInteractive reproduces the original menu-driven flow for users who
+// run the binary with no arguments at all.
+func runInteractive() {
+	var command string
+	var inputPath string
+	var outputPath string
+	fmt.Println("What to do today?")
+	fmt.Println("** OPTIONS **")
+	fmt.Println("1) Compress File")
+	fmt.Println("2) Compress Folder")
+	fmt.Println("3) De-Compress File")
+	fmt.Println("4) De-Compress Folder")
+	fmt.Println("5) Archive Folder (.tar.gz)")
+	fmt.Println("6) Unarchive (.tar.gz)")
+
+	m := make(map[string]string)
+	m["1"] = "compress"
+	m["2"] = "compress-dir"
+	m["3"] = "decompress"
+	m["4"] = "decompress-dir"
+	m["5"] = "archive"
+	m["6"] = "unarchive"
+
+	fmt.Scanln(&command)
+
+	var inputType string
+	if command == "1" || command == "3" || command == "6" {
+		inputType = "File"
+	} else {
+		inputType = "Folder"
+	}
+
+	fmt.Printf("Enter %s names : \n", inputType)
+	fmt.Printf("Input %s  : \n", inputType)
+	fmt.Scanln(&inputPath)
+	fmt.Printf("Output %s  : \n", inputType)
+	fmt.Scanln(&outputPath)
+
+	fmt.Println("Command:", command)
+	fmt.Println("inputPath:", inputPath)
+	fmt.Println("outputPath:", outputPath)
+
+	resolved, ok := m[command]
+	if !ok {
 		fmt.Printf("Unknown command: %s\n", command)
 		comp.PrintUsage()
+		os.Exit(1)
+	}
+
+	compressor := comp.NewFileCompressor(6)
 
+	if err := runCommand(compressor, resolved, inputPath, outputPath); err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 }